@@ -0,0 +1,438 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Label and annotation keys used to identify the pieces of a DseDatacenter
+const (
+	ClusterLabel             = "com.datastax.dse.cluster"
+	DatacenterLabel          = "com.datastax.dse.datacenter"
+	RackLabel                = "com.datastax.dse.rack"
+	SeedNodeLabel            = "com.datastax.dse.seednode"
+	DseNodeState             = "com.datastax.dse.node-state"
+	DseOperatorProgressLabel = "com.datastax.dse.operator-progress"
+)
+
+// DseRack names a failure domain that a set of DSE nodes should be scheduled into
+type DseRack struct {
+	// Name of the rack. This is used both as the Cassandra rack name, and as a
+	// suffix on the generated StatefulSet name.
+	Name string `json:"name"`
+
+	// Zone is the name of a k8s failure-domain (availability zone) that this
+	// rack's pods should be scheduled into.
+	// +optional
+	Zone string `json:"zone,omitempty"`
+}
+
+// StorageClaim describes a single PersistentVolumeClaim that should be created
+// for each pod in the datacenter's StatefulSets.
+//
+// Deprecated: use NamedStorageClaim via DseDatacenterSpec.StorageClaims instead. When
+// StorageClaims is empty, a non-nil StorageClaim is still honored and is translated into a
+// single "dse-data" entry mounted at /var/lib/cassandra for backward compatibility.
+type StorageClaim struct {
+	StorageClassName string                      `json:"storageClassName"`
+	Resources        corev1.ResourceRequirements `json:"resources"`
+}
+
+// NamedStorageClaim describes one PersistentVolumeClaim template to add to every pod in
+// the datacenter's StatefulSets, and where it should be mounted.
+type NamedStorageClaim struct {
+	// Name of the PVC template, e.g. "dse-data", "dse-commitlog", "dse-hints"
+	Name string `json:"name"`
+
+	// MountPath inside the dse container where this volume should be mounted
+	MountPath string `json:"mountPath"`
+
+	// StorageClassName to request the PVC from
+	StorageClassName string `json:"storageClassName"`
+
+	// Resources describes the requested/limit size of the volume
+	Resources corev1.ResourceRequirements `json:"resources"`
+
+	// AccessModes for the PVC. Defaults to ReadWriteOnce.
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+}
+
+// DseDatacenterSpec defines the desired state of a DseDatacenter
+type DseDatacenterSpec struct {
+	// DseClusterName is the name of the Cassandra/DSE cluster this datacenter belongs to
+	DseClusterName string `json:"dseClusterName"`
+
+	// DseVersion is the DSE server version to run, e.g. "6.8.0"
+	DseVersion string `json:"dseVersion"`
+
+	// Size is the number of DSE nodes to run in this datacenter, spread across Racks
+	Size int32 `json:"size"`
+
+	// Racks is the list of named failure domains to spread nodes across. If empty, a
+	// single unnamed rack is used.
+	// +optional
+	Racks []DseRack `json:"racks,omitempty"`
+
+	// Resources describes the compute resource requirements for the dse container
+	// +optional
+	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// StorageClaim describes the PersistentVolumeClaim that will be mounted at
+	// /var/lib/cassandra for every pod in the datacenter.
+	//
+	// Deprecated: use StorageClaims instead.
+	// +optional
+	StorageClaim *StorageClaim `json:"storageClaim,omitempty"`
+
+	// StorageClaims describes the set of PersistentVolumeClaim templates to generate for
+	// every pod in the datacenter, letting commitlog, data and hints live on separate
+	// volumes/StorageClasses. If StorageClaims is empty and StorageClaim is set, StorageClaim
+	// is translated into a single default "dse-data" entry for backward compatibility; when
+	// both are set, StorageClaim is ignored.
+	// +optional
+	StorageClaims []NamedStorageClaim `json:"storageClaims,omitempty"`
+
+	// ServiceAccount is the service account used to run the DSE pods. Defaults to "default".
+	// +optional
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+
+	// ConfigBuilderImage is the image used to run the config builder init container.
+	// +optional
+	ConfigBuilderImage string `json:"configBuilderImage,omitempty"`
+
+	// Config is an opaque JSON blob of cassandra.yaml / dse.yaml overrides.
+	// +optional
+	Config string `json:"config,omitempty"`
+
+	// AllowMultipleNodesPerWorker controls whether the pod anti-affinity rule that keeps
+	// DSE pods off of the same k8s worker node is relaxed.
+	// +optional
+	AllowMultipleNodesPerWorker bool `json:"allowMultipleNodesPerWorker,omitempty"`
+
+	// Topology configures how pods are scheduled relative to zones, racks and nodes. If
+	// unset, pods are required to run in their rack's Zone (when set), matched on the
+	// topology.kubernetes.io/zone label or the deprecated
+	// failure-domain.beta.kubernetes.io/zone label, and no TopologySpreadConstraints are
+	// generated.
+	// +optional
+	Topology *TopologySpec `json:"topology,omitempty"`
+
+	// PodTemplate lets operators customize the generated pod spec: add sidecars (metrics
+	// exporters, log shippers), override resources/env/probes on the dse and
+	// dse-system-logger containers, or attach extra volumes, tolerations and scheduling
+	// constraints. It is deep-merged into the operator-generated pod template by
+	// newStatefulSetForDseDatacenter; containers named "dse" or "dse-system-logger" are
+	// patched in place, and any other container name is appended as an extra sidecar exactly
+	// as given. Appended sidecars get no volume mounts added for them; they can mount the
+	// dse-logs and dse-config volumes by name through their own VolumeMounts.
+	// +optional
+	PodTemplate *corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+}
+
+// TopologySpec controls how DSE pods are scheduled across zones, racks and nodes.
+type TopologySpec struct {
+	// ZoneLabel is the node label key used to identify a node's availability zone.
+	// Defaults to "topology.kubernetes.io/zone". The deprecated
+	// "failure-domain.beta.kubernetes.io/zone" label is always included as a fallback
+	// match, so clusters that only advertise the beta label still schedule correctly.
+	// +optional
+	ZoneLabel string `json:"zoneLabel,omitempty"`
+
+	// PreferZoneAffinity relaxes a rack's Zone from
+	// RequiredDuringSchedulingIgnoredDuringExecution (the default, matching prior operator
+	// behavior) to PreferredDuringSchedulingIgnoredDuringExecution.
+	// +optional
+	PreferZoneAffinity bool `json:"preferZoneAffinity,omitempty"`
+
+	// MaxSkew is passed through to the generated TopologySpreadConstraints, both the
+	// node-level constraint (spreading a rack's pods across nodes) and the zone-level
+	// constraint (spreading racks across zones). Defaults to 1.
+	// +optional
+	MaxSkew int32 `json:"maxSkew,omitempty"`
+
+	// WhenUnsatisfiable is passed through to the generated TopologySpreadConstraints.
+	// Defaults to ScheduleAnyway.
+	// +optional
+	WhenUnsatisfiable corev1.UnsatisfiableConstraintAction `json:"whenUnsatisfiable,omitempty"`
+}
+
+// DseDatacenterStatus defines the observed state of a DseDatacenter
+type DseDatacenterStatus struct {
+	// Conditions records the most recent readiness check outcomes for this datacenter,
+	// keyed by Type. A DatacenterReady condition is added/updated each time the reconciler
+	// runs readiness.CheckDatacenterReady (a single pass, with no waiting or retrying),
+	// recording the last failure if one occurred.
+	// +optional
+	Conditions []DseDatacenterCondition `json:"conditions,omitempty"`
+}
+
+// DseDatacenterConditionType is the type of a DseDatacenterCondition
+type DseDatacenterConditionType string
+
+// DatacenterReady is set once every owned resource (StatefulSets, PVCs, seed Service,
+// Pods, PDB) has passed its readiness check.
+const DatacenterReady DseDatacenterConditionType = "DatacenterReady"
+
+// DseDatacenterCondition is a single observed condition of a DseDatacenter, following the
+// standard Kubernetes condition shape.
+type DseDatacenterCondition struct {
+	Type               DseDatacenterConditionType `json:"type"`
+	Status             corev1.ConditionStatus     `json:"status"`
+	LastTransitionTime metav1.Time                `json:"lastTransitionTime,omitempty"`
+	Reason             string                     `json:"reason,omitempty"`
+	Message            string                     `json:"message,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DseDatacenter is the Schema for the dsedatacenters API
+type DseDatacenter struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DseDatacenterSpec   `json:"spec,omitempty"`
+	Status DseDatacenterStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DseDatacenterList contains a list of DseDatacenter
+type DseDatacenterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DseDatacenter `json:"items"`
+}
+
+// GetRacks returns the configured racks, or a single default rack if none were specified.
+func (dc *DseDatacenterSpec) GetRacks() []DseRack {
+	if len(dc.Racks) == 0 {
+		return []DseRack{{Name: "default"}}
+	}
+	return dc.Racks
+}
+
+// GetSeedCount returns the number of DSE nodes that should be labeled as seeds: one per
+// rack (so every rack has a local seed to bootstrap against), capped at Size so a
+// single-node datacenter doesn't expect more seeds than it has nodes.
+func (dc *DseDatacenterSpec) GetSeedCount() int {
+	seedCount := len(dc.GetRacks())
+	if seedCount < 1 {
+		seedCount = 1
+	}
+	if int32(seedCount) > dc.Size {
+		seedCount = int(dc.Size)
+	}
+	return seedCount
+}
+
+// GetStorageClaims returns the configured storage claim templates, translating the
+// deprecated singular StorageClaim field into a default "dse-data" entry mounted at
+// /var/lib/cassandra when StorageClaims itself is unset.
+func (dc *DseDatacenterSpec) GetStorageClaims() []NamedStorageClaim {
+	if len(dc.StorageClaims) > 0 {
+		return dc.StorageClaims
+	}
+	if dc.StorageClaim != nil {
+		return []NamedStorageClaim{
+			{
+				Name:             "dse-data",
+				MountPath:        "/var/lib/cassandra",
+				StorageClassName: dc.StorageClaim.StorageClassName,
+				Resources:        dc.StorageClaim.Resources,
+			},
+		}
+	}
+	return nil
+}
+
+// GetDseDatacenterServiceName returns the name of the headless service used for CQL/mgmt-api traffic
+func (dc *DseDatacenter) GetDseDatacenterServiceName() string {
+	return dc.Spec.DseClusterName + "-" + dc.Name + "-service"
+}
+
+// GetSeedServiceName returns the name of the headless service that selects only seed nodes
+func (dc *DseDatacenter) GetSeedServiceName() string {
+	return dc.Spec.DseClusterName + "-" + dc.Name + "-seed-service"
+}
+
+// GetAllPodsServiceName returns the name of the headless service that selects all pods, ready or not
+func (dc *DseDatacenter) GetAllPodsServiceName() string {
+	return dc.Spec.DseClusterName + "-" + dc.Name + "-all-pods-service"
+}
+
+// GetClusterLabels returns the labels that identify every resource belonging to the cluster
+func (dc *DseDatacenter) GetClusterLabels() map[string]string {
+	return map[string]string{
+		ClusterLabel: dc.Spec.DseClusterName,
+	}
+}
+
+// GetDatacenterLabels returns the labels that identify every resource belonging to this datacenter
+func (dc *DseDatacenter) GetDatacenterLabels() map[string]string {
+	labels := dc.GetClusterLabels()
+	labels[DatacenterLabel] = dc.Name
+	return labels
+}
+
+// GetRackLabels returns the labels that identify every resource belonging to a particular rack
+func (dc *DseDatacenter) GetRackLabels(rackName string) map[string]string {
+	labels := dc.GetDatacenterLabels()
+	labels[RackLabel] = rackName
+	return labels
+}
+
+// defaultServiceAccount and defaultConfigBuilderImage are the fallbacks applied whenever a
+// DseDatacenter doesn't set the corresponding Spec field. GetServiceAccount and
+// GetConfigBuilderImage are the single source of truth for them: both
+// newStatefulSetForDseDatacenter and the mutating webhook call through these rather than
+// hardcoding their own copy, so the two can't drift apart.
+const (
+	defaultServiceAccount     = "default"
+	defaultConfigBuilderImage = "datastax/dse-config-builder:latest"
+)
+
+// GetServiceAccount returns the service account to run the DSE pods under
+func (dc *DseDatacenter) GetServiceAccount() string {
+	if dc.Spec.ServiceAccount != "" {
+		return dc.Spec.ServiceAccount
+	}
+	return defaultServiceAccount
+}
+
+// GetConfigBuilderImage returns the image to use for the config-builder init container
+func (dc *DseDatacenter) GetConfigBuilderImage() string {
+	if dc.Spec.ConfigBuilderImage != "" {
+		return dc.Spec.ConfigBuilderImage
+	}
+	return defaultConfigBuilderImage
+}
+
+// GetServerImage returns the DSE server image to run
+func (dc *DseDatacenter) GetServerImage() (string, error) {
+	if dc.Spec.DseVersion == "" {
+		return "", fmt.Errorf("cannot build server image, DseVersion is not set")
+	}
+	return fmt.Sprintf("datastax/dse-server:%s", dc.Spec.DseVersion), nil
+}
+
+// GetConfigAsJSON returns the datacenter's Config overrides as the JSON document consumed by
+// the config-builder init container, or "{}" when Config is unset. No seed or cluster values
+// are added to it.
+func (dc *DseDatacenter) GetConfigAsJSON() (string, error) {
+	if dc.Spec.Config == "" {
+		return "{}", nil
+	}
+	return dc.Spec.Config, nil
+}
+
+// GetContainerPorts returns the set of ports the dse container should expose
+func (dc *DseDatacenter) GetContainerPorts() ([]corev1.ContainerPort, error) {
+	return []corev1.ContainerPort{
+		{Name: "native", ContainerPort: 9042},
+		{Name: "mgmt-api", ContainerPort: 8080},
+	}, nil
+}
+
+// DeepCopyObject implements runtime.Object
+func (dc *DseDatacenter) DeepCopyObject() runtime.Object {
+	if dc == nil {
+		return nil
+	}
+	out := new(DseDatacenter)
+	dc.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject implements runtime.Object
+func (dcl *DseDatacenterList) DeepCopyObject() runtime.Object {
+	if dcl == nil {
+		return nil
+	}
+	out := new(DseDatacenterList)
+	out.TypeMeta = dcl.TypeMeta
+	dcl.ListMeta.DeepCopyInto(&out.ListMeta)
+	if dcl.Items != nil {
+		out.Items = make([]DseDatacenter, len(dcl.Items))
+		for i := range dcl.Items {
+			dcl.Items[i].DeepCopyInto(&out.Items[i])
+		}
+	}
+	return out
+}
+
+// DeepCopyInto copies the receiver into out, deep-copying every pointer, slice and map
+// field on Spec and Status so that out shares no mutable state with dc. This is required
+// by controller-runtime's cache, which hands out DeepCopy'd objects on the assumption that
+// mutating one copy can never be observed by another.
+func (dc *DseDatacenter) DeepCopyInto(out *DseDatacenter) {
+	*out = *dc
+	out.TypeMeta = dc.TypeMeta
+	dc.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	dc.Spec.DeepCopyInto(&out.Spec)
+	dc.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopyInto copies the receiver into out
+func (dc *DseDatacenterSpec) DeepCopyInto(out *DseDatacenterSpec) {
+	*out = *dc
+
+	if dc.Racks != nil {
+		out.Racks = make([]DseRack, len(dc.Racks))
+		copy(out.Racks, dc.Racks)
+	}
+
+	dc.Resources.DeepCopyInto(&out.Resources)
+
+	if dc.StorageClaim != nil {
+		out.StorageClaim = new(StorageClaim)
+		*out.StorageClaim = *dc.StorageClaim
+		out.StorageClaim.Resources = *dc.StorageClaim.Resources.DeepCopy()
+	}
+
+	if dc.StorageClaims != nil {
+		out.StorageClaims = make([]NamedStorageClaim, len(dc.StorageClaims))
+		for i := range dc.StorageClaims {
+			dc.StorageClaims[i].DeepCopyInto(&out.StorageClaims[i])
+		}
+	}
+
+	if dc.Topology != nil {
+		out.Topology = new(TopologySpec)
+		*out.Topology = *dc.Topology
+	}
+
+	if dc.PodTemplate != nil {
+		out.PodTemplate = dc.PodTemplate.DeepCopy()
+	}
+}
+
+// DeepCopyInto copies the receiver into out
+func (n *NamedStorageClaim) DeepCopyInto(out *NamedStorageClaim) {
+	*out = *n
+	n.Resources.DeepCopyInto(&out.Resources)
+	if n.AccessModes != nil {
+		out.AccessModes = make([]corev1.PersistentVolumeAccessMode, len(n.AccessModes))
+		copy(out.AccessModes, n.AccessModes)
+	}
+}
+
+// DeepCopyInto copies the receiver into out
+func (s *DseDatacenterStatus) DeepCopyInto(out *DseDatacenterStatus) {
+	*out = *s
+	if s.Conditions != nil {
+		out.Conditions = make([]DseDatacenterCondition, len(s.Conditions))
+		for i := range s.Conditions {
+			s.Conditions[i].DeepCopyInto(&out.Conditions[i])
+		}
+	}
+}
+
+// DeepCopyInto copies the receiver into out
+func (c *DseDatacenterCondition) DeepCopyInto(out *DseDatacenterCondition) {
+	*out = *c
+	c.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+}
@@ -0,0 +1,226 @@
+// Package readiness implements a Helm-3-style resource-readiness gate: rather than
+// trusting a single HTTP probe, it polls every object a DseDatacenter owns and confirms
+// each one has actually settled before the reconciler reports the datacenter as Ready.
+package readiness
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	datastaxv1alpha1 "github.com/riptano/dse-operator/operator/pkg/apis/datastax/v1alpha1"
+	"github.com/riptano/dse-operator/operator/pkg/dsereconciliation"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PollInterval is how long a caller that gets a *NotReadyError back from
+// CheckDatacenterReady should wait before checking again, e.g. as a controller-runtime
+// reconcile.Result{RequeueAfter: readiness.PollInterval}.
+const PollInterval = 2 * time.Second
+
+// CheckFailure describes a single owned object that failed its readiness check.
+type CheckFailure struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+// NotReadyError is returned by CheckDatacenterReady when one or more owned objects are
+// still failing their readiness check. It is not itself a terminal error: callers should
+// requeue after PollInterval rather than retry immediately or give up.
+type NotReadyError struct {
+	Failures []CheckFailure
+}
+
+func (e *NotReadyError) Error() string {
+	msg := fmt.Sprintf("datacenter not ready, %d resource(s) failed their readiness check:", len(e.Failures))
+	for _, f := range e.Failures {
+		msg += fmt.Sprintf("\n  %s/%s: %s", f.Kind, f.Name, f.Reason)
+	}
+	return msg
+}
+
+// CheckDatacenterReady runs a single, non-blocking pass over the StatefulSets, PVCs, seed
+// Service, Pods and PDB owned by rc.DseDatacenter, concurrently, and returns immediately.
+// It does not wait or retry: callers on the reconcile path should requeue after
+// PollInterval when this returns a *NotReadyError, rather than block the reconcile
+// goroutine polling in a loop.
+func CheckDatacenterReady(ctx context.Context, rc *dsereconciliation.ReconciliationContext) error {
+	failures := checkAll(ctx, rc.Client, rc.DseDatacenter)
+	if len(failures) == 0 {
+		return nil
+	}
+	return &NotReadyError{Failures: failures}
+}
+
+// checkAll runs every typed checker concurrently and collects their failures.
+func checkAll(ctx context.Context, cl client.Client, dc *datastaxv1alpha1.DseDatacenter) []CheckFailure {
+	type result struct {
+		failures []CheckFailure
+	}
+
+	checkers := []func() []CheckFailure{
+		func() []CheckFailure { return checkStatefulSets(ctx, cl, dc) },
+		func() []CheckFailure { return checkPVCs(ctx, cl, dc) },
+		func() []CheckFailure { return checkSeedService(ctx, cl, dc) },
+		func() []CheckFailure { return checkPods(ctx, cl, dc) },
+		func() []CheckFailure { return checkPDB(ctx, cl, dc) },
+	}
+
+	results := make(chan result, len(checkers))
+	for _, checker := range checkers {
+		go func(c func() []CheckFailure) {
+			results <- result{failures: c()}
+		}(checker)
+	}
+
+	var failures []CheckFailure
+	for range checkers {
+		r := <-results
+		failures = append(failures, r.failures...)
+	}
+	return failures
+}
+
+func checkStatefulSets(ctx context.Context, cl client.Client, dc *datastaxv1alpha1.DseDatacenter) []CheckFailure {
+	var failures []CheckFailure
+	var list appsv1.StatefulSetList
+	if err := cl.List(ctx, &list, client.InNamespace(dc.Namespace), client.MatchingLabels(dc.GetDatacenterLabels())); err != nil {
+		return []CheckFailure{{Kind: "StatefulSet", Name: "(list)", Reason: err.Error()}}
+	}
+	for i := range list.Items {
+		sts := &list.Items[i]
+		if ok, reason := statefulSetReady(sts); !ok {
+			failures = append(failures, CheckFailure{Kind: "StatefulSet", Name: sts.Name, Reason: reason})
+		}
+	}
+	return failures
+}
+
+// statefulSetReady reports a StatefulSet as ready once every desired replica is Ready and
+// the controller has observed the latest spec generation.
+func statefulSetReady(sts *appsv1.StatefulSet) (bool, string) {
+	if sts.Status.ObservedGeneration < sts.Generation {
+		return false, "controller has not yet observed the latest generation"
+	}
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	if sts.Status.ReadyReplicas != desired {
+		return false, fmt.Sprintf("%d/%d replicas ready", sts.Status.ReadyReplicas, desired)
+	}
+	return true, ""
+}
+
+func checkPVCs(ctx context.Context, cl client.Client, dc *datastaxv1alpha1.DseDatacenter) []CheckFailure {
+	var failures []CheckFailure
+	var list corev1.PersistentVolumeClaimList
+	if err := cl.List(ctx, &list, client.InNamespace(dc.Namespace), client.MatchingLabels(dc.GetDatacenterLabels())); err != nil {
+		return []CheckFailure{{Kind: "PersistentVolumeClaim", Name: "(list)", Reason: err.Error()}}
+	}
+	for i := range list.Items {
+		pvc := &list.Items[i]
+		if pvc.Status.Phase != corev1.ClaimBound {
+			failures = append(failures, CheckFailure{
+				Kind:   "PersistentVolumeClaim",
+				Name:   pvc.Name,
+				Reason: fmt.Sprintf("phase is %s, want Bound", pvc.Status.Phase),
+			})
+		}
+	}
+	return failures
+}
+
+// checkSeedService confirms the seed service has a ready endpoint for every seed the
+// datacenter expects (one per rack, see DseDatacenterSpec.GetSeedCount), since the rest of
+// the cluster bootstraps by contacting seeds through it.
+func checkSeedService(ctx context.Context, cl client.Client, dc *datastaxv1alpha1.DseDatacenter) []CheckFailure {
+	name := dc.GetSeedServiceName()
+
+	var endpoints corev1.Endpoints
+	key := types.NamespacedName{Namespace: dc.Namespace, Name: name}
+	if err := cl.Get(ctx, key, &endpoints); err != nil {
+		return []CheckFailure{{Kind: "Service", Name: name, Reason: err.Error()}}
+	}
+
+	readyAddresses := 0
+	for _, subset := range endpoints.Subsets {
+		readyAddresses += len(subset.Addresses)
+	}
+
+	expectedSeeds := dc.Spec.GetSeedCount()
+	if readyAddresses < expectedSeeds {
+		return []CheckFailure{{
+			Kind:   "Service",
+			Name:   name,
+			Reason: fmt.Sprintf("%d/%d seed endpoints ready", readyAddresses, expectedSeeds),
+		}}
+	}
+	return nil
+}
+
+func checkPods(ctx context.Context, cl client.Client, dc *datastaxv1alpha1.DseDatacenter) []CheckFailure {
+	var failures []CheckFailure
+	var list corev1.PodList
+	if err := cl.List(ctx, &list, client.InNamespace(dc.Namespace), client.MatchingLabels(dc.GetDatacenterLabels())); err != nil {
+		return []CheckFailure{{Kind: "Pod", Name: "(list)", Reason: err.Error()}}
+	}
+	for i := range list.Items {
+		pod := &list.Items[i]
+		if ok, reason := podReady(pod); !ok {
+			failures = append(failures, CheckFailure{Kind: "Pod", Name: pod.Name, Reason: reason})
+		}
+	}
+	return failures
+}
+
+// podReady requires the PodReady condition to be true and every container to report Ready.
+func podReady(pod *corev1.Pod) (bool, string) {
+	ready := false
+	for _, cond := range pod.Status.Conditions {
+		if cond.Type == corev1.PodReady && cond.Status == corev1.ConditionTrue {
+			ready = true
+		}
+	}
+	if !ready {
+		return false, "PodReady condition is not True"
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if !cs.Ready {
+			return false, fmt.Sprintf("container %s is not ready", cs.Name)
+		}
+	}
+	return true, ""
+}
+
+func checkPDB(ctx context.Context, cl client.Client, dc *datastaxv1alpha1.DseDatacenter) []CheckFailure {
+	// No PDB is created for a datacenter with Size < 2, so there is nothing to check.
+	if dc.Spec.Size < 2 {
+		return nil
+	}
+
+	var pdb policyv1beta1.PodDisruptionBudget
+	key := types.NamespacedName{Namespace: dc.Namespace, Name: dc.Name + "-pdb"}
+	if err := cl.Get(ctx, key, &pdb); err != nil {
+		return []CheckFailure{{Kind: "PodDisruptionBudget", Name: key.Name, Reason: err.Error()}}
+	}
+
+	if pdb.Spec.MinAvailable == nil {
+		return nil
+	}
+	minAvailable := pdb.Spec.MinAvailable.IntValue()
+	if int(pdb.Status.CurrentHealthy) < minAvailable {
+		return []CheckFailure{{
+			Kind:   "PodDisruptionBudget",
+			Name:   pdb.Name,
+			Reason: fmt.Sprintf("currentHealthy=%d, want >= %d", pdb.Status.CurrentHealthy, minAvailable),
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package readiness
+
+import (
+	"context"
+	"testing"
+
+	datastaxv1alpha1 "github.com/riptano/dse-operator/operator/pkg/apis/datastax/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestDseDatacenter() *datastaxv1alpha1.DseDatacenter {
+	return &datastaxv1alpha1.DseDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns1"},
+		Spec: datastaxv1alpha1.DseDatacenterSpec{
+			DseClusterName: "test",
+			Size:           2,
+		},
+	}
+}
+
+func newFakeClient(objs ...runtime.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = appsv1.AddToScheme(scheme)
+	_ = corev1.AddToScheme(scheme)
+	return fake.NewFakeClientWithScheme(scheme, objs...)
+}
+
+func TestStatefulSetReadyRequiresObservedGenerationAndReadyReplicas(t *testing.T) {
+	replicas := int32(3)
+	sts := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Generation: 2},
+		Spec:       appsv1.StatefulSetSpec{Replicas: &replicas},
+		Status:     appsv1.StatefulSetStatus{ObservedGeneration: 1, ReadyReplicas: 3},
+	}
+	if ok, _ := statefulSetReady(sts); ok {
+		t.Error("expected not ready: controller has not observed the latest generation")
+	}
+
+	sts.Status.ObservedGeneration = 2
+	sts.Status.ReadyReplicas = 2
+	if ok, _ := statefulSetReady(sts); ok {
+		t.Error("expected not ready: only 2/3 replicas ready")
+	}
+
+	sts.Status.ReadyReplicas = 3
+	if ok, _ := statefulSetReady(sts); !ok {
+		t.Error("expected ready once generation observed and all replicas ready")
+	}
+}
+
+func TestPodReadyRequiresPodReadyConditionAndAllContainers(t *testing.T) {
+	pod := &corev1.Pod{
+		Status: corev1.PodStatus{
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+	if ok, _ := podReady(pod); ok {
+		t.Error("expected not ready: PodReady condition is False")
+	}
+
+	pod.Status.Conditions[0].Status = corev1.ConditionTrue
+	pod.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "dse", Ready: false}}
+	if ok, _ := podReady(pod); ok {
+		t.Error("expected not ready: container not ready")
+	}
+
+	pod.Status.ContainerStatuses[0].Ready = true
+	if ok, _ := podReady(pod); !ok {
+		t.Error("expected ready: PodReady condition True and all containers ready")
+	}
+}
+
+func TestCheckSeedServiceComparesAgainstGetSeedCount(t *testing.T) {
+	dc := newTestDseDatacenter()
+	dc.Spec.Racks = []datastaxv1alpha1.DseRack{{Name: "rack1"}, {Name: "rack2"}}
+
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: dc.GetSeedServiceName(), Namespace: dc.Namespace},
+		Subsets: []corev1.EndpointSubset{
+			{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+		},
+	}
+
+	cl := newFakeClient(endpoints)
+	failures := checkSeedService(context.Background(), cl, dc)
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure with only 1/2 expected seed endpoints ready, got %+v", failures)
+	}
+
+	endpoints.Subsets[0].Addresses = append(endpoints.Subsets[0].Addresses, corev1.EndpointAddress{IP: "10.0.0.2"})
+	cl = newFakeClient(endpoints)
+	if failures := checkSeedService(context.Background(), cl, dc); len(failures) != 0 {
+		t.Errorf("expected no failures once readyAddresses meets GetSeedCount, got %+v", failures)
+	}
+}
+
+func TestCheckPVCsFailsUnlessBound(t *testing.T) {
+	dc := newTestDseDatacenter()
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dse-data-dc1-rack1-0",
+			Namespace: dc.Namespace,
+			Labels:    dc.GetDatacenterLabels(),
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+	}
+
+	cl := newFakeClient(pvc)
+	if failures := checkPVCs(context.Background(), cl, dc); len(failures) != 1 {
+		t.Fatalf("expected 1 failure for a Pending PVC, got %+v", failures)
+	}
+
+	pvc.Status.Phase = corev1.ClaimBound
+	cl = newFakeClient(pvc)
+	if failures := checkPVCs(context.Background(), cl, dc); len(failures) != 0 {
+		t.Errorf("expected no failures for a Bound PVC, got %+v", failures)
+	}
+}
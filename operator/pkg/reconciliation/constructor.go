@@ -9,6 +9,7 @@ import (
 	"github.com/riptano/dse-operator/operator/pkg/dsereconciliation"
 	"github.com/riptano/dse-operator/operator/pkg/httphelper"
 	"github.com/riptano/dse-operator/operator/pkg/oplabels"
+	"github.com/riptano/dse-operator/operator/pkg/readiness"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -85,6 +86,17 @@ func makeGenericHeadlessService(dseDatacenter *datastaxv1alpha1.DseDatacenter) *
 	return &service
 }
 
+// storageClaimEnvVarNames maps well-known storage claim names to the env var that the
+// config-builder init container receives the mount path in, to wire it into
+// cassandra.yaml. Claims with names outside this list are still mounted, they just aren't
+// surfaced to the config builder.
+var storageClaimEnvVarNames = map[string]string{
+	"dse-data":         "DATA_FILE_DIRECTORIES",
+	"dse-commitlog":    "COMMITLOG_DIR",
+	"dse-hints":        "HINTS_DIRECTORY",
+	"dse-saved-caches": "SAVED_CACHES_DIRECTORY",
+}
+
 func newNamespacedNameForStatefulSet(
 	dseDc *datastaxv1alpha1.DseDatacenter,
 	rackName string) types.NamespacedName {
@@ -152,27 +164,38 @@ func newStatefulSetForDseDatacenter(
 		return nil, err
 	}
 
-	// Add storage if storage claim defined
-	if nil != dseDatacenter.Spec.StorageClaim {
-		pvcName := "dse-data"
-		storageClaim := dseDatacenter.Spec.StorageClaim
+	// Add a VolumeClaimTemplate (and matching VolumeMount) for every configured storage
+	// claim, e.g. separate volumes for data, commitlog and hints.
+	var storageClaimEnvVars []corev1.EnvVar
+	for _, claim := range dseDatacenter.Spec.GetStorageClaims() {
+		accessModes := claim.AccessModes
+		if len(accessModes) == 0 {
+			accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		}
+
+		storageClassName := claim.StorageClassName
 		dseVolumeMounts = append(dseVolumeMounts, corev1.VolumeMount{
-			Name:      pvcName,
-			MountPath: "/var/lib/cassandra",
+			Name:      claim.Name,
+			MountPath: claim.MountPath,
 		})
-		volumeCaimTemplates = []corev1.PersistentVolumeClaim{{
+		volumeCaimTemplates = append(volumeCaimTemplates, corev1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: pvcLabels,
-				Name:   pvcName,
+				Name:   claim.Name,
 			},
 			Spec: corev1.PersistentVolumeClaimSpec{
-				AccessModes: []corev1.PersistentVolumeAccessMode{
-					corev1.ReadWriteOnce,
-				},
-				Resources:        storageClaim.Resources,
-				StorageClassName: &storageClaim.StorageClassName,
+				AccessModes:      accessModes,
+				Resources:        claim.Resources,
+				StorageClassName: &storageClassName,
 			},
-		}}
+		})
+
+		if envName, ok := storageClaimEnvVarNames[claim.Name]; ok {
+			storageClaimEnvVars = append(storageClaimEnvVars, corev1.EnvVar{
+				Name:  envName,
+				Value: claim.MountPath,
+			})
+		}
 	}
 
 	ports, err := dseDatacenter.GetContainerPorts()
@@ -184,22 +207,48 @@ func newStatefulSetForDseDatacenter(
 		return nil, err
 	}
 
-	serviceAccount := "default"
-	if dseDatacenter.Spec.ServiceAccount != "" {
-		serviceAccount = dseDatacenter.Spec.ServiceAccount
-	}
+	serviceAccount := dseDatacenter.GetServiceAccount()
 
 	nsName := newNamespacedNameForStatefulSet(dseDatacenter, rackName)
 
+	configInitContainerEnv := []corev1.EnvVar{
+		{
+			Name:  "CONFIG_FILE_DATA",
+			Value: configData,
+		},
+		{
+			Name: "POD_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: "status.podIP",
+				},
+			},
+		},
+		{
+			Name: "RACK_NAME",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					FieldPath: fmt.Sprintf("metadata.labels['%s']", datastaxv1alpha1.RackLabel),
+				},
+			},
+		},
+		{
+			Name:  "DSE_VERSION",
+			Value: dseVersion,
+		},
+	}
+	configInitContainerEnv = append(configInitContainerEnv, storageClaimEnvVars...)
+
 	template := corev1.PodTemplateSpec{
 		ObjectMeta: metav1.ObjectMeta{
 			Labels: podLabels,
 		},
 		Spec: corev1.PodSpec{
 			Affinity: &corev1.Affinity{
-				NodeAffinity:    calculateNodeAffinity(zone),
+				NodeAffinity:    calculateNodeAffinity(zone, dseDatacenter.Spec.Topology),
 				PodAntiAffinity: calculatePodAntiAffinity(dseDatacenter.Spec.AllowMultipleNodesPerWorker),
 			},
+			TopologySpreadConstraints: calculateTopologySpreadConstraints(dseDatacenter, rackName),
 			// workaround for https://cloud.google.com/kubernetes-engine/docs/security-bulletins#may-31-2019
 			SecurityContext: &corev1.PodSecurityContext{
 				RunAsUser:  &userID,
@@ -226,32 +275,7 @@ func newStatefulSetForDseDatacenter(
 				VolumeMounts: []corev1.VolumeMount{
 					dseConfigVolumeMount,
 				},
-				Env: []corev1.EnvVar{
-					{
-						Name:  "CONFIG_FILE_DATA",
-						Value: configData,
-					},
-					{
-						Name: "POD_IP",
-						ValueFrom: &corev1.EnvVarSource{
-							FieldRef: &corev1.ObjectFieldSelector{
-								FieldPath: "status.podIP",
-							},
-						},
-					},
-					{
-						Name: "RACK_NAME",
-						ValueFrom: &corev1.EnvVarSource{
-							FieldRef: &corev1.ObjectFieldSelector{
-								FieldPath: fmt.Sprintf("metadata.labels['%s']", datastaxv1alpha1.RackLabel),
-							},
-						},
-					},
-					{
-						Name:  "DSE_VERSION",
-						Value: dseVersion,
-					},
-				},
+				Env: configInitContainerEnv,
 			}},
 			ServiceAccountName: serviceAccount,
 			Containers: []corev1.Container{
@@ -319,6 +343,10 @@ func newStatefulSetForDseDatacenter(
 
 	_ = httphelper.AddManagementApiServerSecurity(dseDatacenter, &template)
 
+	if dseDatacenter.Spec.PodTemplate != nil {
+		mergePodTemplateSpec(&template, dseDatacenter.Spec.PodTemplate)
+	}
+
 	result := &appsv1.StatefulSet{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      nsName.Name,
@@ -340,8 +368,209 @@ func newStatefulSetForDseDatacenter(
 	return result, nil
 }
 
+// mergePodTemplateSpec deep-merges a user-supplied PodTemplateSpec override into the
+// operator-generated pod template. Containers (and init containers) whose name matches one
+// the operator already generates are patched additively: env vars, volume mounts, resources
+// and probes from the override take precedence, but nothing the operator set is dropped
+// unless the override explicitly replaces it. Container names the operator doesn't know
+// about are appended as sidecars/extra init containers.
+func mergePodTemplateSpec(template *corev1.PodTemplateSpec, override *corev1.PodTemplateSpec) {
+	if override.ObjectMeta.Labels != nil {
+		for k, v := range override.ObjectMeta.Labels {
+			template.ObjectMeta.Labels[k] = v
+		}
+	}
+
+	spec := &template.Spec
+	overrideSpec := &override.Spec
+
+	if len(overrideSpec.Tolerations) > 0 {
+		spec.Tolerations = append(spec.Tolerations, overrideSpec.Tolerations...)
+	}
+
+	if len(overrideSpec.NodeSelector) > 0 {
+		if spec.NodeSelector == nil {
+			spec.NodeSelector = make(map[string]string)
+		}
+		for k, v := range overrideSpec.NodeSelector {
+			spec.NodeSelector[k] = v
+		}
+	}
+
+	if overrideSpec.Affinity != nil {
+		mergeAffinity(spec, overrideSpec.Affinity)
+	}
+
+	if len(overrideSpec.ImagePullSecrets) > 0 {
+		spec.ImagePullSecrets = append(spec.ImagePullSecrets, overrideSpec.ImagePullSecrets...)
+	}
+
+	if overrideSpec.PriorityClassName != "" {
+		spec.PriorityClassName = overrideSpec.PriorityClassName
+	}
+
+	spec.Volumes = mergeVolumes(spec.Volumes, overrideSpec.Volumes)
+	spec.InitContainers = mergeContainers(spec.InitContainers, overrideSpec.InitContainers)
+	spec.Containers = mergeContainers(spec.Containers, overrideSpec.Containers)
+}
+
+// mergeAffinity layers the user's affinity rules on top of the operator-generated
+// node/pod anti-affinity rather than replacing it outright.
+func mergeAffinity(spec *corev1.PodSpec, override *corev1.Affinity) {
+	if spec.Affinity == nil {
+		spec.Affinity = &corev1.Affinity{}
+	}
+
+	if override.NodeAffinity != nil {
+		if spec.Affinity.NodeAffinity == nil {
+			spec.Affinity.NodeAffinity = override.NodeAffinity
+		} else {
+			spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+				spec.Affinity.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+				override.NodeAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+
+			// NodeSelectorTerms within a NodeSelector are OR'd together, but MatchExpressions
+			// and MatchFields within a single term are AND'd. The operator's rack-zone pin is
+			// its own required term, so simply appending the override as an additional term
+			// would OR the two together and silently defeat the zone pin (a pod would
+			// schedule by satisfying either constraint, not both). Cross-multiply instead:
+			// AND the override's requirements into every existing term so both the zone pin
+			// and the override continue to be required together.
+			if overrideRequired := override.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution; overrideRequired != nil {
+				if spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+					spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution = overrideRequired
+				} else {
+					existingTerms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+					var merged []corev1.NodeSelectorTerm
+					for _, existingTerm := range existingTerms {
+						for _, overrideTerm := range overrideRequired.NodeSelectorTerms {
+							merged = append(merged, corev1.NodeSelectorTerm{
+								MatchExpressions: append(
+									append([]corev1.NodeSelectorRequirement{}, existingTerm.MatchExpressions...),
+									overrideTerm.MatchExpressions...),
+								MatchFields: append(
+									append([]corev1.NodeSelectorRequirement{}, existingTerm.MatchFields...),
+									overrideTerm.MatchFields...),
+							})
+						}
+					}
+					spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms = merged
+				}
+			}
+		}
+	}
+
+	if override.PodAffinity != nil {
+		spec.Affinity.PodAffinity = override.PodAffinity
+	}
+
+	if override.PodAntiAffinity != nil {
+		if spec.Affinity.PodAntiAffinity == nil {
+			spec.Affinity.PodAntiAffinity = override.PodAntiAffinity
+		} else {
+			spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution = append(
+				spec.Affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution,
+				override.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution...)
+			spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution = append(
+				spec.Affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution,
+				override.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution...)
+		}
+	}
+}
+
+// mergeVolumes appends override volumes whose name isn't already defined by the operator,
+// so sidecars can reference the shared dse-logs/dse-config volumes or bring their own.
+func mergeVolumes(base []corev1.Volume, override []corev1.Volume) []corev1.Volume {
+	existing := make(map[string]bool, len(base))
+	for _, v := range base {
+		existing[v.Name] = true
+	}
+	for _, v := range override {
+		if !existing[v.Name] {
+			base = append(base, v)
+		}
+	}
+	return base
+}
+
+// mergeContainers patches operator-generated containers (matched by name) with the
+// user-supplied env, resources, probes and extra volume mounts, and appends any
+// container name the operator doesn't already generate as a new sidecar/init container.
+func mergeContainers(base []corev1.Container, override []corev1.Container) []corev1.Container {
+	indexByName := make(map[string]int, len(base))
+	for i, c := range base {
+		indexByName[c.Name] = i
+	}
+
+	for _, oc := range override {
+		if i, found := indexByName[oc.Name]; found {
+			patchContainer(&base[i], oc)
+		} else {
+			base = append(base, oc)
+		}
+	}
+	return base
+}
+
+// patchContainer additively applies the fields of override onto target: env vars and volume
+// mounts are merged by name (override wins on conflict), while resources, image and probes
+// are replaced wholesale when the override sets them.
+func patchContainer(target *corev1.Container, override corev1.Container) {
+	if override.Image != "" {
+		target.Image = override.Image
+	}
+
+	if len(override.Env) > 0 {
+		envByName := make(map[string]int, len(target.Env))
+		for i, e := range target.Env {
+			envByName[e.Name] = i
+		}
+		for _, e := range override.Env {
+			if i, found := envByName[e.Name]; found {
+				target.Env[i] = e
+			} else {
+				target.Env = append(target.Env, e)
+			}
+		}
+	}
+
+	if len(override.VolumeMounts) > 0 {
+		mountByName := make(map[string]int, len(target.VolumeMounts))
+		for i, m := range target.VolumeMounts {
+			mountByName[m.Name] = i
+		}
+		for _, m := range override.VolumeMounts {
+			if i, found := mountByName[m.Name]; found {
+				target.VolumeMounts[i] = m
+			} else {
+				target.VolumeMounts = append(target.VolumeMounts, m)
+			}
+		}
+	}
+
+	if override.Resources.Limits != nil || override.Resources.Requests != nil {
+		target.Resources = override.Resources
+	}
+
+	if override.LivenessProbe != nil {
+		target.LivenessProbe = override.LivenessProbe
+	}
+
+	if override.ReadinessProbe != nil {
+		target.ReadinessProbe = override.ReadinessProbe
+	}
+}
+
 // Create a PodDisruptionBudget object for the DSE Datacenter
+// newPodDisruptionBudgetForDatacenter returns nil when Size < 2: a PDB with
+// minAvailable = Size-1 isn't meaningful for a single-node datacenter, so the caller should
+// skip creating one rather than the CR being rejected outright for a perfectly valid
+// single-node dev/test/CI topology.
 func newPodDisruptionBudgetForDatacenter(dseDatacenter *datastaxv1alpha1.DseDatacenter) *policyv1beta1.PodDisruptionBudget {
+	if dseDatacenter.Spec.Size < 2 {
+		return nil
+	}
+
 	minAvailable := intstr.FromInt(int(dseDatacenter.Spec.Size - 1))
 	labels := dseDatacenter.GetDatacenterLabels()
 	oplabels.AddManagedByLabel(labels)
@@ -375,6 +604,12 @@ func addOperatorProgressLabel(
 
 	labelVal := string(status)
 
+	if status == ready {
+		if err := gateOnDatacenterReadiness(rc); err != nil {
+			return err
+		}
+	}
+
 	dcLabels := rc.DseDatacenter.GetLabels()
 	if dcLabels == nil {
 		dcLabels = make(map[string]string)
@@ -398,26 +633,167 @@ func addOperatorProgressLabel(
 	return nil
 }
 
-// calculateNodeAffinity provides a way to pin all pods within a statefulset to the same zone
-func calculateNodeAffinity(zone string) *corev1.NodeAffinity {
+// gateOnDatacenterReadiness runs a single, non-blocking check of every resource the
+// datacenter owns (StatefulSets, PVCs, seed Service, Pods, PDB) and records the outcome as
+// a DatacenterReady condition before the Ready progress label is allowed to be set. It
+// never blocks the reconcile goroutine: when a *readiness.NotReadyError comes back, the
+// caller is expected to requeue with reconcile.Result{RequeueAfter: readiness.PollInterval}
+// rather than loop here, so one datacenter's bring-up can't stall every other reconcile.
+func gateOnDatacenterReadiness(rc *dsereconciliation.ReconciliationContext) error {
+	err := readiness.CheckDatacenterReady(rc.Ctx, rc)
+
+	condition := datastaxv1alpha1.DseDatacenterCondition{
+		Type:               datastaxv1alpha1.DatacenterReady,
+		LastTransitionTime: metav1.Now(),
+	}
+	if err != nil {
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = "ResourceNotReady"
+		condition.Message = err.Error()
+	} else {
+		condition.Status = corev1.ConditionTrue
+	}
+	setDatacenterCondition(rc.DseDatacenter, condition)
+
+	// Persist the condition regardless of outcome. This matters most on the failure path:
+	// it's the only place the last readiness failure gets surfaced onto the CR, and the
+	// status subresource needs its own Update call since the label update later in
+	// addOperatorProgressLabel won't touch .status.
+	if statusErr := rc.Client.Status().Update(rc.Ctx, rc.DseDatacenter); statusErr != nil {
+		rc.ReqLogger.Error(statusErr, "error updating DatacenterReady condition")
+	}
+
+	if err != nil {
+		rc.ReqLogger.Error(err, "datacenter failed readiness gate")
+		return err
+	}
+	return nil
+}
+
+// setDatacenterCondition upserts a condition by Type, matching the conventional
+// Kubernetes condition-list update pattern.
+func setDatacenterCondition(dc *datastaxv1alpha1.DseDatacenter, condition datastaxv1alpha1.DseDatacenterCondition) {
+	for i, existing := range dc.Status.Conditions {
+		if existing.Type == condition.Type {
+			dc.Status.Conditions[i] = condition
+			return
+		}
+	}
+	dc.Status.Conditions = append(dc.Status.Conditions, condition)
+}
+
+// defaultZoneLabel is the GA node label for availability zone, used unless a
+// TopologySpec.ZoneLabel override is set.
+const defaultZoneLabel = "topology.kubernetes.io/zone"
+
+// betaZoneLabel is the deprecated zone label; it's always included as a fallback
+// NodeSelectorTerm so clusters that only advertise it still schedule correctly.
+const betaZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+
+// calculateNodeAffinity provides a way to pin (or prefer) all pods within a statefulset to
+// the same zone. The zone label is configurable via topology, with the deprecated beta
+// label always OR'd in as a fallback term.
+func calculateNodeAffinity(zone string, topology *datastaxv1alpha1.TopologySpec) *corev1.NodeAffinity {
 	if zone == "" {
 		return nil
 	}
-	return &corev1.NodeAffinity{
-		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
-			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+
+	zoneLabel := defaultZoneLabel
+	preferred := false
+	if topology != nil {
+		if topology.ZoneLabel != "" {
+			zoneLabel = topology.ZoneLabel
+		}
+		preferred = topology.PreferZoneAffinity
+	}
+
+	terms := []corev1.NodeSelectorTerm{
+		{
+			MatchExpressions: []corev1.NodeSelectorRequirement{
 				{
-					MatchExpressions: []corev1.NodeSelectorRequirement{
-						{
-							Key:      "failure-domain.beta.kubernetes.io/zone",
-							Operator: corev1.NodeSelectorOpIn,
-							Values:   []string{zone},
-						},
-					},
+					Key:      zoneLabel,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{zone},
 				},
 			},
 		},
 	}
+	if zoneLabel != betaZoneLabel {
+		terms = append(terms, corev1.NodeSelectorTerm{
+			MatchExpressions: []corev1.NodeSelectorRequirement{
+				{
+					Key:      betaZoneLabel,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   []string{zone},
+				},
+			},
+		})
+	}
+
+	nodeSelector := &corev1.NodeSelector{NodeSelectorTerms: terms}
+
+	if preferred {
+		var preferredTerms []corev1.PreferredSchedulingTerm
+		for _, term := range terms {
+			preferredTerms = append(preferredTerms, corev1.PreferredSchedulingTerm{
+				Weight:     100,
+				Preference: term,
+			})
+		}
+		return &corev1.NodeAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: preferredTerms,
+		}
+	}
+
+	return &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: nodeSelector,
+	}
+}
+
+// calculateTopologySpreadConstraints builds the TopologySpreadConstraints that spread a
+// rack's pods evenly across nodes, and spread racks evenly across zones. It returns nil
+// when no Topology is configured, preserving the prior (pod-anti-affinity-only) behavior.
+func calculateTopologySpreadConstraints(
+	dseDatacenter *datastaxv1alpha1.DseDatacenter,
+	rackName string) []corev1.TopologySpreadConstraint {
+
+	topology := dseDatacenter.Spec.Topology
+	if topology == nil {
+		return nil
+	}
+
+	maxSkew := topology.MaxSkew
+	if maxSkew <= 0 {
+		maxSkew = 1
+	}
+
+	whenUnsatisfiable := topology.WhenUnsatisfiable
+	if whenUnsatisfiable == "" {
+		whenUnsatisfiable = corev1.ScheduleAnyway
+	}
+
+	zoneLabel := defaultZoneLabel
+	if topology.ZoneLabel != "" {
+		zoneLabel = topology.ZoneLabel
+	}
+
+	rackLabels := dseDatacenter.GetRackLabels(rackName)
+	datacenterLabels := dseDatacenter.GetDatacenterLabels()
+
+	return []corev1.TopologySpreadConstraint{
+		{
+			MaxSkew:           maxSkew,
+			TopologyKey:       "kubernetes.io/hostname",
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: rackLabels},
+		},
+		{
+			MaxSkew:           maxSkew,
+			TopologyKey:       zoneLabel,
+			WhenUnsatisfiable: whenUnsatisfiable,
+			LabelSelector:     &metav1.LabelSelector{MatchLabels: datacenterLabels},
+		},
+	}
 }
 
 // calculatePodAntiAffinity provides a way to keep the dse pods of a statefulset away from other dse pods
@@ -0,0 +1,151 @@
+package reconciliation
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestPatchContainerMergesEnvAndVolumeMountsByName(t *testing.T) {
+	target := &corev1.Container{
+		Name:  "dse",
+		Image: "datastax/dse-server:6.8.0",
+		Env: []corev1.EnvVar{
+			{Name: "CLUSTER_NAME", Value: "test"},
+			{Name: "RACK_NAME", Value: "rack1"},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "dse-logs", MountPath: "/var/log/cassandra"},
+		},
+	}
+
+	override := corev1.Container{
+		Name: "dse",
+		Env: []corev1.EnvVar{
+			{Name: "RACK_NAME", Value: "rack1-override"},
+			{Name: "JVM_OPTS", Value: "-Xmx4g"},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "dse-logs", MountPath: "/opt/dse/logs"},
+			{Name: "jmx-config", MountPath: "/etc/jmx"},
+		},
+	}
+
+	patchContainer(target, override)
+
+	wantEnv := []corev1.EnvVar{
+		{Name: "CLUSTER_NAME", Value: "test"},
+		{Name: "RACK_NAME", Value: "rack1-override"},
+		{Name: "JVM_OPTS", Value: "-Xmx4g"},
+	}
+	if !reflect.DeepEqual(target.Env, wantEnv) {
+		t.Errorf("Env = %+v, want %+v", target.Env, wantEnv)
+	}
+
+	wantMounts := []corev1.VolumeMount{
+		{Name: "dse-logs", MountPath: "/opt/dse/logs"},
+		{Name: "jmx-config", MountPath: "/etc/jmx"},
+	}
+	if !reflect.DeepEqual(target.VolumeMounts, wantMounts) {
+		t.Errorf("VolumeMounts = %+v, want %+v", target.VolumeMounts, wantMounts)
+	}
+
+	if target.Image != "datastax/dse-server:6.8.0" {
+		t.Errorf("Image = %q, want unchanged since override left it empty", target.Image)
+	}
+}
+
+func TestPatchContainerReplacesResourcesAndProbesWholesale(t *testing.T) {
+	target := &corev1.Container{
+		Name: "dse",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+		},
+		LivenessProbe: &corev1.Probe{InitialDelaySeconds: 30},
+	}
+
+	override := corev1.Container{
+		Name: "dse",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		},
+		LivenessProbe: &corev1.Probe{InitialDelaySeconds: 60},
+	}
+
+	patchContainer(target, override)
+
+	if !reflect.DeepEqual(target.Resources, override.Resources) {
+		t.Errorf("Resources = %+v, want %+v", target.Resources, override.Resources)
+	}
+	if target.LivenessProbe.InitialDelaySeconds != 60 {
+		t.Errorf("LivenessProbe.InitialDelaySeconds = %d, want 60", target.LivenessProbe.InitialDelaySeconds)
+	}
+}
+
+func TestMergeContainersPatchesExistingAndAppendsNew(t *testing.T) {
+	base := []corev1.Container{
+		{Name: "dse", Image: "datastax/dse-server:6.8.0"},
+		{Name: "dse-system-logger", Image: "busybox"},
+	}
+
+	override := []corev1.Container{
+		{Name: "dse", Env: []corev1.EnvVar{{Name: "JVM_OPTS", Value: "-Xmx4g"}}},
+		{Name: "jmx-exporter", Image: "prom/jmx-exporter"},
+	}
+
+	merged := mergeContainers(base, override)
+
+	if len(merged) != 3 {
+		t.Fatalf("len(merged) = %d, want 3", len(merged))
+	}
+	if merged[0].Name != "dse" || len(merged[0].Env) != 1 {
+		t.Errorf("dse container was not patched in place: %+v", merged[0])
+	}
+	if merged[2].Name != "jmx-exporter" {
+		t.Errorf("merged[2].Name = %q, want jmx-exporter appended as sidecar", merged[2].Name)
+	}
+}
+
+func TestMergeAffinityAndsOverrideRequiredTermsIntoExisting(t *testing.T) {
+	spec := &corev1.PodSpec{
+		Affinity: &corev1.Affinity{
+			NodeAffinity: &corev1.NodeAffinity{
+				RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+					NodeSelectorTerms: []corev1.NodeSelectorTerm{
+						{
+							MatchExpressions: []corev1.NodeSelectorRequirement{
+								{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1a"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	override := &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{
+					{
+						MatchExpressions: []corev1.NodeSelectorRequirement{
+							{Key: "disktype", Operator: corev1.NodeSelectorOpIn, Values: []string{"ssd"}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	mergeAffinity(spec, override)
+
+	terms := spec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 {
+		t.Fatalf("len(terms) = %d, want 1 (cross-multiplied, not OR'd as a second term)", len(terms))
+	}
+	if len(terms[0].MatchExpressions) != 2 {
+		t.Fatalf("len(terms[0].MatchExpressions) = %d, want 2 (zone pin AND override ANDed into the same term)", len(terms[0].MatchExpressions))
+	}
+}
@@ -0,0 +1,89 @@
+package reconciliation
+
+import (
+	"testing"
+
+	datastaxv1alpha1 "github.com/riptano/dse-operator/operator/pkg/apis/datastax/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestCalculateNodeAffinityReturnsNilWithoutAZone(t *testing.T) {
+	if got := calculateNodeAffinity("", nil); got != nil {
+		t.Errorf("calculateNodeAffinity(\"\", nil) = %+v, want nil", got)
+	}
+}
+
+func TestCalculateNodeAffinityDefaultsToRequiredWithBetaFallback(t *testing.T) {
+	affinity := calculateNodeAffinity("us-east-1a", nil)
+
+	if affinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		t.Fatal("expected a required node affinity by default")
+	}
+	terms := affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 2 {
+		t.Fatalf("len(terms) = %d, want 2 (GA zone label + deprecated beta fallback)", len(terms))
+	}
+	if terms[0].MatchExpressions[0].Key != defaultZoneLabel {
+		t.Errorf("terms[0] key = %q, want %q", terms[0].MatchExpressions[0].Key, defaultZoneLabel)
+	}
+	if terms[1].MatchExpressions[0].Key != betaZoneLabel {
+		t.Errorf("terms[1] key = %q, want %q", terms[1].MatchExpressions[0].Key, betaZoneLabel)
+	}
+}
+
+func TestCalculateNodeAffinityPreferZoneAffinityUsesPreferredTerms(t *testing.T) {
+	affinity := calculateNodeAffinity("us-east-1a", &datastaxv1alpha1.TopologySpec{PreferZoneAffinity: true})
+
+	if affinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		t.Error("expected no required node affinity when PreferZoneAffinity is set")
+	}
+	if len(affinity.PreferredDuringSchedulingIgnoredDuringExecution) != 2 {
+		t.Fatalf("len(preferred) = %d, want 2", len(affinity.PreferredDuringSchedulingIgnoredDuringExecution))
+	}
+}
+
+func TestCalculateNodeAffinityBetaZoneLabelOmitsBetaFallback(t *testing.T) {
+	affinity := calculateNodeAffinity("us-east-1a", &datastaxv1alpha1.TopologySpec{ZoneLabel: betaZoneLabel})
+
+	terms := affinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	if len(terms) != 1 {
+		t.Fatalf("len(terms) = %d, want 1 when the configured zone label already is the beta label", len(terms))
+	}
+}
+
+func TestCalculateTopologySpreadConstraintsNilWithoutTopology(t *testing.T) {
+	dc := &datastaxv1alpha1.DseDatacenter{}
+	if got := calculateTopologySpreadConstraints(dc, "rack1"); got != nil {
+		t.Errorf("calculateTopologySpreadConstraints() = %+v, want nil", got)
+	}
+}
+
+func TestCalculateTopologySpreadConstraintsDefaultsMaxSkewAndWhenUnsatisfiable(t *testing.T) {
+	dc := &datastaxv1alpha1.DseDatacenter{
+		Spec: datastaxv1alpha1.DseDatacenterSpec{
+			DseClusterName: "test",
+			Topology:       &datastaxv1alpha1.TopologySpec{},
+		},
+	}
+	dc.Name = "dc1"
+
+	constraints := calculateTopologySpreadConstraints(dc, "rack1")
+	if len(constraints) != 2 {
+		t.Fatalf("len(constraints) = %d, want 2 (node-level + zone-level)", len(constraints))
+	}
+	for _, c := range constraints {
+		if c.MaxSkew != 1 {
+			t.Errorf("MaxSkew = %d, want default 1", c.MaxSkew)
+		}
+		if c.WhenUnsatisfiable != corev1.ScheduleAnyway {
+			t.Errorf("WhenUnsatisfiable = %q, want %q", c.WhenUnsatisfiable, corev1.ScheduleAnyway)
+		}
+	}
+	if constraints[0].TopologyKey != "kubernetes.io/hostname" {
+		t.Errorf("constraints[0].TopologyKey = %q, want kubernetes.io/hostname", constraints[0].TopologyKey)
+	}
+	if constraints[1].TopologyKey != defaultZoneLabel {
+		t.Errorf("constraints[1].TopologyKey = %q, want %q", constraints[1].TopologyKey, defaultZoneLabel)
+	}
+}
@@ -0,0 +1,18 @@
+package webhook
+
+import "flag"
+
+// CertDir is where the webhook server looks for tls.crt/tls.key. It's populated either by
+// cert-manager (via a CSR/Certificate mounted at this path) or by a self-signed secret
+// mounted at this path; either way the server doesn't need to know which. The operator does
+// not create the certificate itself.
+var CertDir string
+
+// BindFlags registers the --webhook-cert-dir flag on fs, mirroring the way the rest of the
+// operator's cmd/manager flags are bound. Defaults to "/tmp/k8s-webhook-server/serving-certs",
+// matching controller-runtime's own default so cert-manager's default injection path works
+// out of the box.
+func BindFlags(fs *flag.FlagSet) {
+	fs.StringVar(&CertDir, "webhook-cert-dir", "/tmp/k8s-webhook-server/serving-certs",
+		"directory containing tls.crt/tls.key for the DseDatacenter admission webhooks")
+}
@@ -0,0 +1,54 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	datastaxv1alpha1 "github.com/riptano/dse-operator/operator/pkg/apis/datastax/v1alpha1"
+	"github.com/riptano/dse-operator/operator/pkg/oplabels"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// DseDatacenterDefaulter fills in the same defaults newStatefulSetForDseDatacenter applies
+// implicitly, and stamps cluster-wide labels, so the persisted spec is unambiguous.
+type DseDatacenterDefaulter struct {
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler.
+func (m *DseDatacenterDefaulter) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var dc datastaxv1alpha1.DseDatacenter
+	if err := m.decoder.Decode(req, &dc); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// GetServiceAccount/GetConfigBuilderImage are the single source of truth for these
+	// fallbacks; calling through them here means this webhook can't drift from what
+	// newStatefulSetForDseDatacenter actually runs.
+	dc.Spec.ServiceAccount = dc.GetServiceAccount()
+	dc.Spec.ConfigBuilderImage = dc.GetConfigBuilderImage()
+
+	labels := dc.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+	for k, v := range dc.GetClusterLabels() {
+		labels[k] = v
+	}
+	oplabels.AddManagedByLabel(labels)
+	dc.SetLabels(labels)
+
+	marshaled, err := json.Marshal(dc)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaled)
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (m *DseDatacenterDefaulter) InjectDecoder(d *admission.Decoder) error {
+	m.decoder = d
+	return nil
+}
@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+// validatePath and mutatePath are the HTTP paths the Validating and
+// MutatingWebhookConfiguration route DseDatacenter admission requests to, following the
+// standard "validate"/"mutate" path convention.
+const (
+	validatePath = "/validate-datastax-v1alpha1-dsedatacenter"
+	mutatePath   = "/mutate-datastax-v1alpha1-dsedatacenter"
+)
+
+// AddToManager registers the DseDatacenter validating and mutating webhooks on mgr's
+// webhook server, with the server's CertDir set from --webhook-cert-dir. That directory must
+// already contain tls.crt/tls.key, provisioned either by cert-manager or a mounted
+// self-signed secret.
+func AddToManager(mgr manager.Manager) error {
+	server := mgr.GetWebhookServer()
+	server.CertDir = CertDir
+
+	server.Register(validatePath, &webhook.Admission{Handler: &DseDatacenterValidator{}})
+	server.Register(mutatePath, &webhook.Admission{Handler: &DseDatacenterDefaulter{}})
+
+	return nil
+}
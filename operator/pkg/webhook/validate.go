@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	datastaxv1alpha1 "github.com/riptano/dse-operator/operator/pkg/apis/datastax/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// allowedDseVersions is the set of server versions the operator knows how to build an
+// image reference and config-builder payload for.
+var allowedDseVersions = map[string]bool{
+	"6.8.0": true,
+	"6.8.1": true,
+	"6.8.2": true,
+	"6.8.3": true,
+}
+
+// DseDatacenterValidator rejects DseDatacenter creates/updates that would otherwise
+// produce a broken StatefulSet, PDB or scheduling rule only visible once it's already
+// live in the cluster.
+type DseDatacenterValidator struct {
+	decoder *admission.Decoder
+}
+
+// Handle implements admission.Handler.
+func (v *DseDatacenterValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var dc datastaxv1alpha1.DseDatacenter
+	if err := v.decoder.Decode(req, &dc); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	if errs := validateCreate(&dc); len(errs) > 0 {
+		return admission.Denied(joinErrors(errs))
+	}
+
+	if req.OldObject.Raw != nil {
+		var old datastaxv1alpha1.DseDatacenter
+		if err := v.decoder.DecodeRaw(req.OldObject, &old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if errs := validateUpdate(&old, &dc); len(errs) > 0 {
+			return admission.Denied(joinErrors(errs))
+		}
+	}
+
+	return admission.Allowed("")
+}
+
+// InjectDecoder implements admission.DecoderInjector.
+func (v *DseDatacenterValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+// validateCreate enforces the invariants newStatefulSetForDseDatacenter and
+// newPodDisruptionBudgetForDatacenter silently assume hold true.
+func validateCreate(dc *datastaxv1alpha1.DseDatacenter) []string {
+	var errs []string
+
+	if dc.Spec.DseClusterName == "" {
+		errs = append(errs, "spec.dseClusterName must not be empty")
+	} else if msgs := validation.IsDNS1123Subdomain(dc.Spec.DseClusterName); len(msgs) > 0 {
+		errs = append(errs, fmt.Sprintf("spec.dseClusterName: %s", joinErrors(msgs)))
+	}
+
+	if msgs := validation.IsDNS1123Subdomain(dc.Name); len(msgs) > 0 {
+		errs = append(errs, fmt.Sprintf("metadata.name: %s", joinErrors(msgs)))
+	}
+
+	if dc.Spec.Size < 1 {
+		errs = append(errs, "spec.size must be >= 1")
+	}
+	// Size < 2 is a valid single-node dev/test/CI topology; it just means
+	// newPodDisruptionBudgetForDatacenter skips creating a PDB for it rather than
+	// producing one with a meaningless minAvailable.
+
+	if !allowedDseVersions[dc.Spec.DseVersion] {
+		errs = append(errs, fmt.Sprintf("spec.dseVersion %q is not in the supported version list", dc.Spec.DseVersion))
+	}
+
+	seenRackNames := make(map[string]bool, len(dc.Spec.Racks))
+	for _, rack := range dc.Spec.Racks {
+		if msgs := validation.IsDNS1123Label(rack.Name); len(msgs) > 0 {
+			errs = append(errs, fmt.Sprintf("spec.racks[%s]: %s", rack.Name, joinErrors(msgs)))
+		}
+		if seenRackNames[rack.Name] {
+			errs = append(errs, fmt.Sprintf("spec.racks: rack name %q is duplicated", rack.Name))
+		}
+		seenRackNames[rack.Name] = true
+	}
+
+	return errs
+}
+
+// validateUpdate additionally rejects changes to fields that StatefulSet treats as
+// immutable (selector, service name) or that would otherwise strand existing storage.
+func validateUpdate(old, new *datastaxv1alpha1.DseDatacenter) []string {
+	var errs []string
+
+	if old.Spec.DseClusterName != new.Spec.DseClusterName {
+		errs = append(errs, "spec.dseClusterName is immutable: it is baked into the StatefulSet's generated name and service selector")
+	}
+
+	if len(old.Spec.Racks) > 0 && len(new.Spec.Racks) < len(old.Spec.Racks) {
+		errs = append(errs, "spec.racks: existing racks cannot be removed")
+	}
+	for i, rack := range old.Spec.Racks {
+		if i < len(new.Spec.Racks) && new.Spec.Racks[i].Name != rack.Name {
+			errs = append(errs, fmt.Sprintf("spec.racks[%d].name is immutable: was %q", i, rack.Name))
+		}
+	}
+
+	oldStorageClass := storageClassNameOf(old)
+	newStorageClass := storageClassNameOf(new)
+	if oldStorageClass != "" && oldStorageClass != newStorageClass {
+		errs = append(errs, "storage class is immutable once PersistentVolumeClaims have been created for the datacenter")
+	}
+
+	return errs
+}
+
+func storageClassNameOf(dc *datastaxv1alpha1.DseDatacenter) string {
+	claims := dc.Spec.GetStorageClaims()
+	if len(claims) == 0 {
+		return ""
+	}
+	return claims[0].StorageClassName
+}
+
+func joinErrors(errs []string) string {
+	out := ""
+	for i, e := range errs {
+		if i > 0 {
+			out += "; "
+		}
+		out += e
+	}
+	return out
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"testing"
+
+	datastaxv1alpha1 "github.com/riptano/dse-operator/operator/pkg/apis/datastax/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newValidDseDatacenter() *datastaxv1alpha1.DseDatacenter {
+	return &datastaxv1alpha1.DseDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1"},
+		Spec: datastaxv1alpha1.DseDatacenterSpec{
+			DseClusterName: "test-cluster",
+			DseVersion:     "6.8.0",
+			Size:           3,
+			Racks: []datastaxv1alpha1.DseRack{
+				{Name: "rack1"},
+				{Name: "rack2"},
+			},
+		},
+	}
+}
+
+func TestValidateCreateAcceptsAValidSingleNodeDatacenter(t *testing.T) {
+	dc := newValidDseDatacenter()
+	dc.Spec.Size = 1
+	dc.Spec.Racks = nil
+
+	if errs := validateCreate(dc); len(errs) != 0 {
+		t.Errorf("expected a single-node datacenter to be accepted, got errors: %v", errs)
+	}
+}
+
+func TestValidateCreateRejectsUnknownDseVersion(t *testing.T) {
+	dc := newValidDseDatacenter()
+	dc.Spec.DseVersion = "5.0.0"
+
+	errs := validateCreate(dc)
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly 1 error for an unsupported DseVersion, got %v", errs)
+	}
+}
+
+func TestValidateCreateRejectsInvalidClusterName(t *testing.T) {
+	dc := newValidDseDatacenter()
+	dc.Spec.DseClusterName = "Not_A_DNS_Label"
+
+	if errs := validateCreate(dc); len(errs) == 0 {
+		t.Error("expected an error for a DseClusterName that isn't a valid DNS-1123 subdomain")
+	}
+}
+
+func TestValidateCreateRejectsDuplicateRackNames(t *testing.T) {
+	dc := newValidDseDatacenter()
+	dc.Spec.Racks = []datastaxv1alpha1.DseRack{{Name: "rack1"}, {Name: "rack1"}}
+
+	if errs := validateCreate(dc); len(errs) == 0 {
+		t.Error("expected an error for duplicate rack names")
+	}
+}
+
+func TestValidateCreateRejectsSizeLessThanOne(t *testing.T) {
+	dc := newValidDseDatacenter()
+	dc.Spec.Size = 0
+
+	if errs := validateCreate(dc); len(errs) == 0 {
+		t.Error("expected an error for spec.size < 1")
+	}
+}
+
+func TestValidateUpdateRejectsClusterNameChange(t *testing.T) {
+	old := newValidDseDatacenter()
+	new := newValidDseDatacenter()
+	new.Spec.DseClusterName = "renamed-cluster"
+
+	if errs := validateUpdate(old, new); len(errs) == 0 {
+		t.Error("expected an error for changing spec.dseClusterName")
+	}
+}
+
+func TestValidateUpdateRejectsRackRemovalAndRename(t *testing.T) {
+	old := newValidDseDatacenter()
+
+	removed := newValidDseDatacenter()
+	removed.Spec.Racks = old.Spec.Racks[:1]
+	if errs := validateUpdate(old, removed); len(errs) == 0 {
+		t.Error("expected an error for removing an existing rack")
+	}
+
+	renamed := newValidDseDatacenter()
+	renamed.Spec.Racks[0].Name = "renamed-rack"
+	if errs := validateUpdate(old, renamed); len(errs) == 0 {
+		t.Error("expected an error for renaming an existing rack")
+	}
+}
+
+func TestValidateUpdateAllowsAddingANewRack(t *testing.T) {
+	old := newValidDseDatacenter()
+	new := newValidDseDatacenter()
+	new.Spec.Racks = append(new.Spec.Racks, datastaxv1alpha1.DseRack{Name: "rack3"})
+
+	if errs := validateUpdate(old, new); len(errs) != 0 {
+		t.Errorf("expected adding a new rack to be allowed, got errors: %v", errs)
+	}
+}
+
+func TestValidateUpdateRejectsStorageClassChange(t *testing.T) {
+	old := newValidDseDatacenter()
+	old.Spec.StorageClaims = []datastaxv1alpha1.NamedStorageClaim{
+		{Name: "dse-data", MountPath: "/var/lib/cassandra", StorageClassName: "ssd"},
+	}
+	new := newValidDseDatacenter()
+	new.Spec.StorageClaims = []datastaxv1alpha1.NamedStorageClaim{
+		{Name: "dse-data", MountPath: "/var/lib/cassandra", StorageClassName: "standard"},
+	}
+
+	if errs := validateUpdate(old, new); len(errs) == 0 {
+		t.Error("expected an error for changing the storage class once PVCs may already exist")
+	}
+}